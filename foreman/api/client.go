@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/wayfair/terraform-provider-utils/log"
+)
+
+// Client stores the configuration needed to talk to a Foreman server's
+// REST API. All of the resource-specific API calls (eg. CreateHostgroup,
+// ReadHostgroup, ...) are implemented as methods on this type.
+type Client struct {
+	// Hostname (including scheme) of the Foreman server, eg.
+	// "https://foreman.example.com"
+	Hostname string
+	// Username used for HTTP basic auth against the Foreman API
+	Username string
+	// Password used for HTTP basic auth against the Foreman API
+	Password string
+	// Whether to skip TLS certificate verification when talking to the
+	// Foreman server
+	InsecureSkipVerify bool
+
+	httpClient *http.Client
+}
+
+// NewClient constructs a Client from the hostname/username/password
+// triple supplied by the provider configuration.
+func NewClient(hostname string, username string, password string, insecureSkipVerify bool) *Client {
+	return &Client{
+		Hostname:           hostname,
+		Username:           username,
+		Password:           password,
+		InsecureSkipVerify: insecureSkipVerify,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+			},
+		},
+	}
+}
+
+// SendAndParse issues an HTTP request against the Foreman API and decodes
+// the JSON response body into out. If out is nil, the response body is
+// discarded after the status code is checked. A non-2xx response is
+// returned as an error containing the response body for context. The
+// supplied ctx is attached to the request so a cancellation or deadline
+// from Terraform aborts the in-flight call.
+func (c *Client) SendAndParse(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reqBody []byte
+	var err error
+
+	if body != nil {
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshalling request body: %s", err.Error())
+		}
+	}
+
+	url := c.Hostname + path
+	log.Debugf("[%s] %s: %s", method, url, string(reqBody))
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.Username, c.Password)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf(
+			"unexpected response from Foreman API [%s %s]: %d: %s",
+			method, path, resp.StatusCode, string(respBody),
+		)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,15 @@
+package api
+
+// ForemanObject is embedded in every Foreman API resource struct. It holds
+// the set of attributes that every Foreman entity shares, regardless of
+// which endpoint it is read from.
+type ForemanObject struct {
+	// Unique identifier assigned to the object by Foreman
+	Id int `json:"id,omitempty"`
+	// Human readable name of the object
+	Name string `json:"name,omitempty"`
+	// Timestamp the object was created at
+	CreatedAt string `json:"created_at,omitempty"`
+	// Timestamp the object was last updated at
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
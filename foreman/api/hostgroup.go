@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// HostgroupEndpointPrefix is the base API path for hostgroup resources
+	HostgroupEndpointPrefix = "/api/hostgroups"
+)
+
+// ForemanHostgroup represents a Foreman hostgroup. Hostgroups are organized
+// in a tree-like structure and inherit values from their parent
+// hostgroup(s).
+type ForemanHostgroup struct {
+	// Inherits the base object's attributes
+	ForemanObject
+
+	// Fullname of the hostgroup. The title is a path-like string from the
+	// head of the hostgroup tree down to this hostgroup, in the form
+	// "<parent 1>/<parent 2>/.../<name>".
+	Title string `json:"title,omitempty"`
+
+	// ID of the architecture associated with this hostgroup
+	ArchitectureId int `json:"architecture_id,omitempty"`
+	// ID of the compute profile associated with this hostgroup
+	ComputeProfileId int `json:"compute_profile_id,omitempty"`
+	// ID of the domain associated with this hostgroup
+	DomainId int `json:"domain_id,omitempty"`
+	// ID of the environment associated with this hostgroup
+	EnvironmentId int `json:"environment_id,omitempty"`
+	// ID of the media associated with this hostgroup
+	MediaId int `json:"medium_id,omitempty"`
+	// ID of the operating system associated with this hostgroup
+	OperatingSystemId int `json:"operatingsystem_id,omitempty"`
+	// Parameters associated with this hostgroup
+	Parameters []ForemanHostgroupParameter `json:"-"`
+	// ID of the parent hostgroup
+	ParentId int `json:"parent_id,omitempty"`
+	// ID of the partition table associated with this hostgroup
+	PartitionTableId int `json:"ptable_id,omitempty"`
+	// ID of the smart proxy acting as the puppet certificate authority
+	// server for this hostgroup
+	PuppetCAProxyId int `json:"puppet_ca_proxy_id,omitempty"`
+	// ID of the smart proxy acting as the puppet proxy server for this
+	// hostgroup
+	PuppetProxyId int `json:"puppet_proxy_id,omitempty"`
+	// ID of the realm associated with this hostgroup
+	RealmId int `json:"realm_id,omitempty"`
+	// ID of the subnet associated with this hostgroup
+	SubnetId int `json:"subnet_id,omitempty"`
+
+	// -- Katello Content Attributes --
+	//
+	// These are only meaningful on Foreman instances running Katello and
+	// are omitted from the request payload entirely (via "omitempty")
+	// rather than sent as zero values, so non-Katello servers never see
+	// them.
+
+	// ID of the Katello content view associated with this hostgroup
+	ContentViewId int `json:"content_view_id,omitempty"`
+	// ID of the Katello lifecycle environment associated with this
+	// hostgroup
+	LifecycleEnvironmentId int `json:"lifecycle_environment_id,omitempty"`
+	// ID of the Katello content source (capsule) associated with this
+	// hostgroup
+	ContentSourceId int `json:"content_source_id,omitempty"`
+	// ID of the Katello repository used as the kickstart repository for
+	// this hostgroup
+	KickstartRepositoryId int `json:"kickstart_repository_id,omitempty"`
+}
+
+// ForemanHostgroupParameter represents a single parameter attached to a
+// hostgroup. Parameters are managed through a nested endpoint
+// ("/api/hostgroups/:id/parameters") rather than as part of the hostgroup
+// payload itself.
+type ForemanHostgroupParameter struct {
+	// Unique identifier assigned to the parameter by Foreman
+	ID int64 `json:"id,omitempty"`
+	// Name of the parameter
+	Name string `json:"name"`
+	// Value of the parameter
+	Value string `json:"value"`
+	// Timestamp the parameter was created at
+	CreatedAt string `json:"created_at,omitempty"`
+	// Timestamp the parameter was last updated at
+	UpdatedAt string `json:"updated_at,omitempty"`
+	// Priority of the parameter. Higher priority parameters take
+	// precedence when a host inherits parameters from multiple levels of
+	// the hostgroup tree.
+	Priority int64 `json:"priority,omitempty"`
+}
+
+// hostgroupJSON wraps a ForemanHostgroup in the "hostgroup" envelope the
+// Foreman API expects on create/update.
+type hostgroupJSON struct {
+	Hostgroup *ForemanHostgroup `json:"hostgroup"`
+}
+
+// hostgroupParameterJSON wraps a ForemanHostgroupParameter in the
+// "parameter" envelope the Foreman API expects on create/update.
+type hostgroupParameterJSON struct {
+	Parameter *ForemanHostgroupParameter `json:"parameter"`
+}
+
+// -----------------------------------------------------------------------------
+// CRUD Implementation
+// -----------------------------------------------------------------------------
+
+// CreateHostgroup creates a new hostgroup in Foreman, then syncs its
+// parameters (if any) against the nested parameters endpoint.
+func (c *Client) CreateHostgroup(ctx context.Context, h *ForemanHostgroup) (*ForemanHostgroup, error) {
+	created := new(ForemanHostgroup)
+	if err := c.SendAndParse(ctx, "POST", HostgroupEndpointPrefix, hostgroupJSON{Hostgroup: h}, created); err != nil {
+		return nil, err
+	}
+
+	if err := c.syncHostgroupParameters(ctx, created.Id, nil, h.Parameters); err != nil {
+		return nil, err
+	}
+
+	return c.ReadHostgroup(ctx, created.Id)
+}
+
+// ReadHostgroup reads a hostgroup, including its parameters, from Foreman.
+func (c *Client) ReadHostgroup(ctx context.Context, id int) (*ForemanHostgroup, error) {
+	h := new(ForemanHostgroup)
+	path := fmt.Sprintf("%s/%d", HostgroupEndpointPrefix, id)
+	if err := c.SendAndParse(ctx, "GET", path, nil, h); err != nil {
+		return nil, err
+	}
+
+	params, err := c.readHostgroupParameters(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	h.Parameters = params
+
+	return h, nil
+}
+
+// UpdateHostgroup updates an existing hostgroup in Foreman, then reconciles
+// its parameters against the nested parameters endpoint.
+func (c *Client) UpdateHostgroup(ctx context.Context, h *ForemanHostgroup) (*ForemanHostgroup, error) {
+	updated := new(ForemanHostgroup)
+	path := fmt.Sprintf("%s/%d", HostgroupEndpointPrefix, h.Id)
+	if err := c.SendAndParse(ctx, "PUT", path, hostgroupJSON{Hostgroup: h}, updated); err != nil {
+		return nil, err
+	}
+
+	existing, err := c.readHostgroupParameters(ctx, h.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.syncHostgroupParameters(ctx, h.Id, existing, h.Parameters); err != nil {
+		return nil, err
+	}
+
+	return c.ReadHostgroup(ctx, h.Id)
+}
+
+// DeleteHostgroup deletes a hostgroup from Foreman. Its parameters are
+// removed along with it, so no separate parameter cleanup is required.
+func (c *Client) DeleteHostgroup(ctx context.Context, id int) error {
+	path := fmt.Sprintf("%s/%d", HostgroupEndpointPrefix, id)
+	return c.SendAndParse(ctx, "DELETE", path, nil, nil)
+}
+
+// hostgroupQueryResponse wraps the search results returned from the
+// hostgroup index endpoint.
+type hostgroupQueryResponse struct {
+	Results []ForemanHostgroup `json:"results"`
+}
+
+// QueryHostgroupByTitle looks up a hostgroup by its full title path (eg.
+// "Production/Webservers") using the Foreman search API. An error is
+// returned if zero or more than one hostgroup matches.
+func (c *Client) QueryHostgroupByTitle(ctx context.Context, title string) (*ForemanHostgroup, error) {
+	search := fmt.Sprintf(`title="%s"`, title)
+	path := fmt.Sprintf("%s?search=%s", HostgroupEndpointPrefix, url.QueryEscape(search))
+
+	var resp hostgroupQueryResponse
+	if err := c.SendAndParse(ctx, "GET", path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	switch len(resp.Results) {
+	case 0:
+		return nil, fmt.Errorf("no hostgroup found matching title %q", title)
+	case 1:
+		return c.ReadHostgroup(ctx, resp.Results[0].Id)
+	default:
+		return nil, fmt.Errorf("more than one hostgroup matched title %q", title)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Parameter Helpers
+// -----------------------------------------------------------------------------
+
+// readHostgroupParameters lists the parameters currently attached to a
+// hostgroup.
+func (c *Client) readHostgroupParameters(ctx context.Context, hostgroupId int) ([]ForemanHostgroupParameter, error) {
+	var params []ForemanHostgroupParameter
+	path := fmt.Sprintf("%s/%d/parameters", HostgroupEndpointPrefix, hostgroupId)
+	if err := c.SendAndParse(ctx, "GET", path, nil, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// syncHostgroupParameters reconciles the desired set of parameters against
+// the hostgroup's existing parameters by name: parameters present only in
+// desired are created, parameters present in both whose value changed are
+// updated, and parameters present only in existing are deleted.
+func (c *Client) syncHostgroupParameters(ctx context.Context, hostgroupId int, existing []ForemanHostgroupParameter, desired []ForemanHostgroupParameter) error {
+	existingByName := make(map[string]ForemanHostgroupParameter, len(existing))
+	for _, p := range existing {
+		existingByName[p.Name] = p
+	}
+
+	desiredByName := make(map[string]bool, len(desired))
+	for _, p := range desired {
+		desiredByName[p.Name] = true
+
+		if old, found := existingByName[p.Name]; !found {
+			if err := c.createHostgroupParameter(ctx, hostgroupId, p); err != nil {
+				return err
+			}
+		} else if old.Value != p.Value {
+			if err := c.updateHostgroupParameter(ctx, hostgroupId, old.ID, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, p := range existing {
+		if !desiredByName[p.Name] {
+			if err := c.deleteHostgroupParameter(ctx, hostgroupId, p.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) createHostgroupParameter(ctx context.Context, hostgroupId int, p ForemanHostgroupParameter) error {
+	path := fmt.Sprintf("%s/%d/parameters", HostgroupEndpointPrefix, hostgroupId)
+	return c.SendAndParse(ctx, "POST", path, hostgroupParameterJSON{Parameter: &p}, nil)
+}
+
+func (c *Client) updateHostgroupParameter(ctx context.Context, hostgroupId int, parameterId int64, p ForemanHostgroupParameter) error {
+	path := fmt.Sprintf("%s/%d/parameters/%d", HostgroupEndpointPrefix, hostgroupId, parameterId)
+	return c.SendAndParse(ctx, "PUT", path, hostgroupParameterJSON{Parameter: &p}, nil)
+}
+
+func (c *Client) deleteHostgroupParameter(ctx context.Context, hostgroupId int, parameterId int64) error {
+	path := fmt.Sprintf("%s/%d/parameters/%d", HostgroupEndpointPrefix, hostgroupId, parameterId)
+	return c.SendAndParse(ctx, "DELETE", path, nil, nil)
+}
@@ -0,0 +1,84 @@
+package foreman
+
+import (
+	"context"
+
+	"github.com/wayfair/terraform-provider-foreman/foreman/api"
+	"github.com/wayfair/terraform-provider-utils/log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceForemanHostgroup() *schema.Resource {
+	// copy the base resource's schema, making every attribute read-only
+	// (Computed) except for the lookup keys ("title" and "name"/"parent_id")
+	// which stay Optional so callers can supply whichever identifies the
+	// hostgroup they want
+	r := resourceForemanHostgroup()
+
+	ds := map[string]*schema.Schema{}
+	for k, v := range r.Schema {
+		dsSchema := *v
+
+		switch k {
+		case "title":
+			dsSchema.Computed = false
+			dsSchema.Optional = true
+			dsSchema.Required = false
+		case "name":
+			dsSchema.Computed = false
+			dsSchema.Optional = true
+			dsSchema.Required = false
+		case "parent_id":
+			dsSchema.Computed = false
+			dsSchema.Optional = true
+		default:
+			dsSchema.Computed = true
+			dsSchema.Optional = false
+			dsSchema.Required = false
+			dsSchema.ValidateFunc = nil
+		}
+
+		ds[k] = &dsSchema
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceForemanHostgroupRead,
+		Schema:      ds,
+	}
+}
+
+// dataSourceForemanHostgroupRead resolves a hostgroup by its "title" path
+// attribute, or by "name" combined with "parent_id" when "title" is not
+// supplied, then populates the same computed attributes the
+// foreman_hostgroup resource does.
+func dataSourceForemanHostgroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Tracef("data_source_foreman_hostgroup.go#Read")
+
+	client := meta.(*api.Client)
+
+	title, hasTitle := d.GetOk("title")
+	if !hasTitle {
+		name, hasName := d.GetOk("name")
+		if !hasName {
+			return diag.Errorf("one of \"title\" or \"name\" must be set to look up a foreman_hostgroup")
+		}
+
+		title = name.(string)
+		if parentId, ok := d.GetOk("parent_id"); ok {
+			parent, err := client.ReadHostgroup(ctx, parentId.(int))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			title = parent.Title + "/" + name.(string)
+		}
+	}
+
+	hostgroup, err := client.QueryHostgroupByTitle(ctx, title.(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return setResourceDataFromForemanHostgroup(d, hostgroup)
+}
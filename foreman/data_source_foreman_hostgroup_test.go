@@ -0,0 +1,37 @@
+package foreman
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// NOTE(ALL): this acceptance test expects a hostgroup titled
+//   "Common/DataSourceTest" to already exist on the target Foreman
+//   instance - it is not created by the test itself.
+func TestAccForemanDataSourceHostgroup_basic(t *testing.T) {
+	hostgroupTitle := "Common/DataSourceTest"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccForemanDataSourceHostgroupConfig(hostgroupTitle),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.foreman_hostgroup.test", "title", hostgroupTitle),
+					resource.TestCheckResourceAttrSet("data.foreman_hostgroup.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccForemanDataSourceHostgroupConfig(title string) string {
+	return fmt.Sprintf(`
+data "foreman_hostgroup" "test" {
+  title = "%s"
+}
+`, title)
+}
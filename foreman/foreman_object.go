@@ -0,0 +1,27 @@
+package foreman
+
+import (
+	"strconv"
+
+	"github.com/wayfair/terraform-provider-foreman/foreman/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// buildForemanObject constructs a ForemanObject struct from a resource
+// data reference. This is shared across all of the foreman_* resources
+// since every Foreman entity has an id/name/created_at/updated_at.
+func buildForemanObject(d *schema.ResourceData) *api.ForemanObject {
+	obj := api.ForemanObject{}
+
+	// d.Id() is "" before the object is created, which leaves Id at 0
+	if id, err := strconv.Atoi(d.Id()); err == nil {
+		obj.Id = id
+	}
+
+	if name, ok := d.GetOk("name"); ok {
+		obj.Name = name.(string)
+	}
+
+	return &obj
+}
@@ -0,0 +1,62 @@
+package foreman
+
+import (
+	"sort"
+
+	"github.com/wayfair/terraform-provider-foreman/foreman/api"
+)
+
+// hostgroupParameterConfigNames extracts the ordered list of parameter
+// names out of a "parameters" TypeList attribute as it currently sits on
+// a *schema.ResourceData, eg. via d.Get("parameters").([]interface{}).
+func hostgroupParameterConfigNames(raw []interface{}) []string {
+	names := make([]string, len(raw))
+	for i, r := range raw {
+		names[i] = r.(map[string]interface{})["name"].(string)
+	}
+	return names
+}
+
+// hostgroupParameterNames extracts the ordered list of names out of an
+// already-expanded parameter slice.
+func hostgroupParameterNames(params []api.ForemanHostgroupParameter) []string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// orderHostgroupParametersLikeConfig reorders params to match the order
+// of names in configNames (matched by "Name"), appending any parameter
+// whose name isn't in configNames (eg. one Foreman added/renamed
+// server-side) at the end, in the order Foreman returned it.
+//
+// "parameters" is a schema.TypeList, so its element order is significant
+// to Terraform's diffing, but Foreman's parameter endpoints don't
+// guarantee they'll return parameters in the order they were declared in
+// HCL. Writing the server's order straight back to state would produce a
+// perpetual reorder diff that never converges even though nothing has
+// actually changed.
+func orderHostgroupParametersLikeConfig(configNames []string, params []api.ForemanHostgroupParameter) []api.ForemanHostgroupParameter {
+	index := make(map[string]int, len(configNames))
+	for i, name := range configNames {
+		index[name] = i
+	}
+
+	ordered := make([]api.ForemanHostgroupParameter, len(params))
+	copy(ordered, params)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		iIdx, iOk := index[ordered[i].Name]
+		jIdx, jOk := index[ordered[j].Name]
+		switch {
+		case iOk && jOk:
+			return iIdx < jIdx
+		case iOk != jOk:
+			return iOk
+		default:
+			return false
+		}
+	})
+	return ordered
+}
@@ -0,0 +1,60 @@
+package foreman
+
+import (
+	"github.com/wayfair/terraform-provider-foreman/foreman/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Provider returns the *schema.Provider for the Foreman provider.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"hostname": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FOREMAN_SERVER_HOSTNAME", nil),
+				Description: "Hostname (including scheme) of the Foreman server.",
+			},
+			"username": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FOREMAN_CLIENT_USERNAME", nil),
+				Description: "Username used to authenticate against the Foreman API.",
+			},
+			"password": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("FOREMAN_CLIENT_PASSWORD", nil),
+				Description: "Password used to authenticate against the Foreman API.",
+			},
+			"insecure": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to skip TLS certificate verification when talking to the Foreman server.",
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"foreman_hostgroup":      resourceForemanHostgroup(),
+			"foreman_hostgroup_tree": resourceForemanHostgroupTree(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"foreman_hostgroup": dataSourceForemanHostgroup(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	return api.NewClient(
+		d.Get("hostname").(string),
+		d.Get("username").(string),
+		d.Get("password").(string),
+		d.Get("insecure").(bool),
+	), nil
+}
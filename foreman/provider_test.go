@@ -0,0 +1,26 @@
+package foreman
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var testAccProviders map[string]*schema.Provider
+var testAccProvider *schema.Provider
+
+func init() {
+	testAccProvider = Provider()
+	testAccProviders = map[string]*schema.Provider{
+		"foreman": testAccProvider,
+	}
+}
+
+func testAccPreCheck(t *testing.T) {
+	for _, envVar := range []string{"FOREMAN_SERVER_HOSTNAME", "FOREMAN_CLIENT_USERNAME", "FOREMAN_CLIENT_PASSWORD"} {
+		if os.Getenv(envVar) == "" {
+			t.Fatalf("%s must be set for acceptance tests", envVar)
+		}
+	}
+}
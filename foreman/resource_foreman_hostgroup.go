@@ -1,27 +1,31 @@
 package foreman
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/wayfair/terraform-provider-foreman/foreman/api"
 	"github.com/wayfair/terraform-provider-utils/autodoc"
 	"github.com/wayfair/terraform-provider-utils/log"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceForemanHostgroup() *schema.Resource {
 	return &schema.Resource{
 
-		Create: resourceForemanHostgroupCreate,
-		Read:   resourceForemanHostgroupRead,
-		Update: resourceForemanHostgroupUpdate,
-		Delete: resourceForemanHostgroupDelete,
+		CreateContext: resourceForemanHostgroupCreate,
+		ReadContext:   resourceForemanHostgroupRead,
+		UpdateContext: resourceForemanHostgroupUpdate,
+		DeleteContext: resourceForemanHostgroupDelete,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceForemanHostgroupImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -179,6 +183,43 @@ func resourceForemanHostgroup() *schema.Resource {
 				ValidateFunc: validation.IntAtLeast(0),
 				Description:  "ID of the subnet associated with the hostgroup.",
 			},
+
+			// -- Katello Content Attributes --
+
+			"content_view_id": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description: "ID of the Katello content view associated with this " +
+					"hostgroup. Only applicable on Foreman instances running Katello.",
+			},
+
+			"lifecycle_environment_id": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description: "ID of the Katello lifecycle environment associated with " +
+					"this hostgroup. Only applicable on Foreman instances running " +
+					"Katello.",
+			},
+
+			"content_source_id": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description: "ID of the Katello content source (capsule) associated " +
+					"with this hostgroup. Only applicable on Foreman instances " +
+					"running Katello.",
+			},
+
+			"kickstart_repository_id": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description: "ID of the Katello repository used as the kickstart " +
+					"repository for this hostgroup. Only applicable on Foreman " +
+					"instances running Katello.",
+			},
 		},
 	}
 }
@@ -230,24 +271,28 @@ func buildForemanHostgroup(d *schema.ResourceData) *api.ForemanHostgroup {
 		hostgroup.OperatingSystemId = attr.(int)
 	}
 
-	// I don't know what black magic needs to happen for running tests
-	// uncommenting the following will cause tests to fail with type errors
-	//if attr, ok = d.GetOk("parameters.#"); ok {
-	//	params := make([]api.ForemanHostgroupParameter, attr.(int))
-	//	for i := 0; i < attr.(int); i++ {
-	//		idx := strconv.Itoa(i)
-	//		param := api.ForemanHostgroupParameter{
-	//			ID:        d.Get("parameters." + idx + "id").(int64),
-	//			Name:      d.Get("parameters." + idx + "name").(string),
-	//			Value:     d.Get("parameters." + idx + "value").(string),
-	//			CreatedAt: d.Get("parameters." + idx + "created_at").(string),
-	//			UpdatedAt: d.Get("parameters." + idx + "updated_at").(string),
-	//			Priority:  d.Get("parameters." + idx + "priority").(int64),
-	//		}
-	//		params = append(params, param)
-	//	}
-	//	hostgroup.Parameters = params
-	//}
+	if attr, ok = d.GetOk("parameters.#"); ok {
+		numParams := attr.(int)
+		params := make([]api.ForemanHostgroupParameter, numParams)
+		for i := 0; i < numParams; i++ {
+			idx := strconv.Itoa(i)
+			prefix := "parameters." + idx + "."
+			// NOTE(ALL): the "parameters" schema declares id/priority as
+			//   TypeInt (computed server-side values), but
+			//   ForemanHostgroupParameter stores them as int64 to match the
+			//   Foreman API's JSON representation, hence the explicit
+			//   conversions below.
+			params[i] = api.ForemanHostgroupParameter{
+				ID:        int64(d.Get(prefix + "id").(int)),
+				Name:      d.Get(prefix + "name").(string),
+				Value:     d.Get(prefix + "value").(string),
+				CreatedAt: d.Get(prefix + "created_at").(string),
+				UpdatedAt: d.Get(prefix + "updated_at").(string),
+				Priority:  int64(d.Get(prefix + "priority").(int)),
+			}
+		}
+		hostgroup.Parameters = params
+	}
 
 	if attr, ok = d.GetOk("parent_id"); ok {
 		hostgroup.ParentId = attr.(int)
@@ -273,16 +318,53 @@ func buildForemanHostgroup(d *schema.ResourceData) *api.ForemanHostgroup {
 		hostgroup.SubnetId = attr.(int)
 	}
 
+	if attr, ok = d.GetOk("content_view_id"); ok {
+		hostgroup.ContentViewId = attr.(int)
+	}
+
+	if attr, ok = d.GetOk("lifecycle_environment_id"); ok {
+		hostgroup.LifecycleEnvironmentId = attr.(int)
+	}
+
+	if attr, ok = d.GetOk("content_source_id"); ok {
+		hostgroup.ContentSourceId = attr.(int)
+	}
+
+	if attr, ok = d.GetOk("kickstart_repository_id"); ok {
+		hostgroup.KickstartRepositoryId = attr.(int)
+	}
+
 	return &hostgroup
 }
 
 // setResourceDataFromForemanHostgroup sets a ResourceData's attributes from
-// the attributes of the supplied ForemanHostgroup struct
-func setResourceDataFromForemanHostgroup(d *schema.ResourceData, fh *api.ForemanHostgroup) {
+// the attributes of the supplied ForemanHostgroup struct. Any attribute
+// that fails to set is reported as a diag.Diagnostic with its
+// AttributePath pointing at the offending field, rather than just logged,
+// so the failure is visible to the operator running terraform.
+func setResourceDataFromForemanHostgroup(d *schema.ResourceData, fh *api.ForemanHostgroup) diag.Diagnostics {
 	log.Tracef("resource_foreman_hostgroup.go#setResourceDataFromForemanHostgroup")
 
-	params := make([]map[string]interface{}, len(fh.Parameters))
-	for k, v := range fh.Parameters {
+	var diags diag.Diagnostics
+
+	setAttr := func(key string, value interface{}) {
+		if err := d.Set(key, value); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("error setting hostgroup %s", key),
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath(key),
+			})
+		}
+	}
+
+	orderedParameters := orderHostgroupParametersLikeConfig(
+		hostgroupParameterConfigNames(d.Get("parameters").([]interface{})),
+		fh.Parameters,
+	)
+
+	params := make([]map[string]interface{}, len(orderedParameters))
+	for k, v := range orderedParameters {
 		param := make(map[string]interface{})
 		param["name"] = v.Name
 		param["value"] = v.Value
@@ -295,58 +377,34 @@ func setResourceDataFromForemanHostgroup(d *schema.ResourceData, fh *api.Foreman
 
 	d.SetId(strconv.Itoa(fh.Id))
 
-	if err := d.Set("title", fh.Title); err != nil {
-		log.Errorf("error setting hostgroup title: %s", err)
-	}
-	if err := d.Set("name", fh.Name); err != nil {
-		log.Errorf("error setting hostgroup name: %s", err)
-	}
-	if err := d.Set("architecture_id", fh.ArchitectureId); err != nil {
-		log.Errorf("error setting hostgroup architecture_id: %s", err)
-	}
-	if err := d.Set("compute_profile_id", fh.ComputeProfileId); err != nil {
-		log.Errorf("error setting hostgroup compute_profile_id: %s", err)
-	}
-	if err := d.Set("domain_id", fh.DomainId); err != nil {
-		log.Errorf("error setting hostgroup domain_id: %s", err)
-	}
-	if err := d.Set("environment_id", fh.EnvironmentId); err != nil {
-		log.Errorf("error setting hostgroup environment_id: %s", err)
-	}
-	if err := d.Set("medium_id", fh.MediaId); err != nil {
-		log.Errorf("error setting hostgroup medium_id: %s", err)
-	}
-	if err := d.Set("operatingsystem_id", fh.OperatingSystemId); err != nil {
-		log.Errorf("error setting hostgroup operatingsystem_id: %s", err)
-	}
-	if err := d.Set("parameters", params); err != nil {
-		log.Errorf("error setting hostgroup parameters: %s", err)
-	}
-	if err := d.Set("parent_id", fh.ParentId); err != nil {
-		log.Errorf("error setting hostgroup parent_id: %s", err)
-	}
-	if err := d.Set("ptable_id", fh.PartitionTableId); err != nil {
-		log.Errorf("error setting hostgroup ptable_id: %s", err)
-	}
-	if err := d.Set("puppet_ca_proxy_id", fh.PuppetCAProxyId); err != nil {
-		log.Errorf("error setting hostgroup puppet_ca_proxy_id: %s", err)
-	}
-	if err := d.Set("puppet_proxy_id", fh.PuppetProxyId); err != nil {
-		log.Errorf("error setting hostgroup puppet_proxy_id: %s", err)
-	}
-	if err := d.Set("realm_id", fh.RealmId); err != nil {
-		log.Errorf("error setting hostgroup realm_id: %s", err)
-	}
-	if err := d.Set("subnet_id", fh.SubnetId); err != nil {
-		log.Errorf("error setting hostgroup subnet_id: %s", err)
-	}
+	setAttr("title", fh.Title)
+	setAttr("name", fh.Name)
+	setAttr("architecture_id", fh.ArchitectureId)
+	setAttr("compute_profile_id", fh.ComputeProfileId)
+	setAttr("domain_id", fh.DomainId)
+	setAttr("environment_id", fh.EnvironmentId)
+	setAttr("medium_id", fh.MediaId)
+	setAttr("operatingsystem_id", fh.OperatingSystemId)
+	setAttr("parameters", params)
+	setAttr("parent_id", fh.ParentId)
+	setAttr("ptable_id", fh.PartitionTableId)
+	setAttr("puppet_ca_proxy_id", fh.PuppetCAProxyId)
+	setAttr("puppet_proxy_id", fh.PuppetProxyId)
+	setAttr("realm_id", fh.RealmId)
+	setAttr("subnet_id", fh.SubnetId)
+	setAttr("content_view_id", fh.ContentViewId)
+	setAttr("lifecycle_environment_id", fh.LifecycleEnvironmentId)
+	setAttr("content_source_id", fh.ContentSourceId)
+	setAttr("kickstart_repository_id", fh.KickstartRepositoryId)
+
+	return diags
 }
 
 // -----------------------------------------------------------------------------
 // Resource CRUD Operations
 // -----------------------------------------------------------------------------
 
-func resourceForemanHostgroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceForemanHostgroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Tracef("resource_foreman_hostgroup.go#Create")
 
 	client := meta.(*api.Client)
@@ -354,19 +412,17 @@ func resourceForemanHostgroupCreate(d *schema.ResourceData, meta interface{}) er
 
 	log.Debugf("ForemanHostgroup: [%+v]", h)
 
-	createdHostgroup, createErr := client.CreateHostgroup(h)
+	createdHostgroup, createErr := client.CreateHostgroup(ctx, h)
 	if createErr != nil {
-		return createErr
+		return diag.FromErr(createErr)
 	}
 
 	log.Debugf("Created ForemanHostgroup: [%+v]", createdHostgroup)
 
-	setResourceDataFromForemanHostgroup(d, createdHostgroup)
-
-	return nil
+	return setResourceDataFromForemanHostgroup(d, createdHostgroup)
 }
 
-func resourceForemanHostgroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceForemanHostgroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Tracef("resource_foreman_hostgroup.go#Read")
 
 	client := meta.(*api.Client)
@@ -374,44 +430,41 @@ func resourceForemanHostgroupRead(d *schema.ResourceData, meta interface{}) erro
 
 	log.Debugf("ForemanHostgroup: [%+v]", h)
 
-	readHostgroup, readErr := client.ReadHostgroup(h.Id)
+	readHostgroup, readErr := client.ReadHostgroup(ctx, h.Id)
 	if readErr != nil {
-		return readErr
+		return diag.FromErr(readErr)
 	}
 
 	log.Debugf("Read ForemanHostgroup: [%+v]", readHostgroup)
 
-	setResourceDataFromForemanHostgroup(d, readHostgroup)
-
-	return nil
+	return setResourceDataFromForemanHostgroup(d, readHostgroup)
 }
 
-func resourceForemanHostgroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceForemanHostgroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Tracef("resource_foreman_hostgroup.go#Update")
 
 	// TODO(ALL): 404 errors here (for v.1.11.4 ) - i think we need to
 	//   concatentate the id with the title, replacing forward slash with a dash?
 	//   getting weird behavior when updating a hostgroup aside from updating the
-	//   hostgroup's name
+	//   hostgroup's name. diag.FromErr below at least surfaces the underlying
+	//   HTTP status/body now instead of a bare error string.
 
 	client := meta.(*api.Client)
 	h := buildForemanHostgroup(d)
 
 	log.Debugf("ForemanHostgroup: [%+v]", h)
 
-	updatedHostgroup, updateErr := client.UpdateHostgroup(h)
+	updatedHostgroup, updateErr := client.UpdateHostgroup(ctx, h)
 	if updateErr != nil {
-		return updateErr
+		return diag.FromErr(updateErr)
 	}
 
 	log.Debugf("Updated ForemanHostgroup: [%+v]", updatedHostgroup)
 
-	setResourceDataFromForemanHostgroup(d, updatedHostgroup)
-
-	return nil
+	return setResourceDataFromForemanHostgroup(d, updatedHostgroup)
 }
 
-func resourceForemanHostgroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceForemanHostgroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Tracef("resource_foreman_hostgroup.go#Delete")
 
 	client := meta.(*api.Client)
@@ -421,5 +474,32 @@ func resourceForemanHostgroupDelete(d *schema.ResourceData, meta interface{}) er
 
 	// NOTE(ALL): d.SetId("") is automatically called by terraform assuming delete
 	//   returns no errors
-	return client.DeleteHostgroup(h.Id)
+	if deleteErr := client.DeleteHostgroup(ctx, h.Id); deleteErr != nil {
+		return diag.FromErr(deleteErr)
+	}
+
+	return nil
+}
+
+// resourceForemanHostgroupImport allows "terraform import" to accept either
+// the Foreman numeric ID (eg. "42") or the hostgroup's title path (eg.
+// "Production/Webservers"), which is the form shown throughout Foreman's
+// UI. A title path is detected by the presence of a "/" or by failing to
+// parse as an integer, in which case it is resolved to a numeric ID via
+// the search API before the normal read takes over.
+func resourceForemanHostgroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Tracef("resource_foreman_hostgroup.go#Import")
+
+	importId := d.Id()
+
+	if _, err := strconv.Atoi(importId); err != nil || strings.Contains(importId, "/") {
+		client := meta.(*api.Client)
+		hostgroup, lookupErr := client.QueryHostgroupByTitle(ctx, importId)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		d.SetId(strconv.Itoa(hostgroup.Id))
+	}
+
+	return []*schema.ResourceData{d}, nil
 }
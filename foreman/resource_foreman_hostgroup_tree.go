@@ -0,0 +1,769 @@
+package foreman
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wayfair/terraform-provider-foreman/foreman/api"
+	"github.com/wayfair/terraform-provider-utils/autodoc"
+	"github.com/wayfair/terraform-provider-utils/log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// -----------------------------------------------------------------------------
+// Schema
+// -----------------------------------------------------------------------------
+
+// hostgroupTreeNodeSchema is the attribute set carried by every "node"
+// entry in a foreman_hostgroup_tree. It intentionally mirrors the foreign
+// key attributes of resourceForemanHostgroup(), minus "parent_id"/"title"
+// which this resource derives from "path" instead. A flat list of nodes
+// keyed by "key" is used in place of a recursive "children" block so the
+// schema doesn't run into Terraform's block nesting limits for deep
+// trees.
+func hostgroupTreeNodeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+
+		"key": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			Description: "Stable identifier for this node, unique within the tree. " +
+				"Unlike \"path\", the key does not change when the node is " +
+				"re-parented, so Update can tell a move (same key, new path) " +
+				"apart from a destroy/create (no key in common).",
+		},
+
+		"path": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			Description: "Slash-separated path of this node relative to the tree's " +
+				"root, eg. \"web\" for the root node or \"web/frontend\" for a " +
+				"child of \"web\". The last path segment is used as the " +
+				"hostgroup's name.",
+		},
+
+		"id": &schema.Schema{
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "ID of the hostgroup Foreman assigned to this node.",
+		},
+
+		"architecture_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the architecture associated with this hostgroup.",
+		},
+
+		"compute_profile_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the compute profile associated with this hostgroup.",
+		},
+
+		"domain_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the domain associated with this hostgroup.",
+		},
+
+		"environment_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the environment associated with this hostgroup.",
+		},
+
+		"medium_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the media associated with this hostgroup.",
+		},
+
+		"operatingsystem_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the operating system associated with this hostgroup.",
+		},
+
+		"ptable_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the partition table associated with this hostgroup.",
+		},
+
+		"puppet_ca_proxy_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description: "ID of the smart proxy acting as the puppet certificate " +
+				"authority server for this hostgroup.",
+		},
+
+		"puppet_proxy_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description: "ID of the smart proxy acting as the puppet proxy " +
+				"server for this hostgroup.",
+		},
+
+		"realm_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the realm associated with this hostgroup.",
+		},
+
+		"subnet_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "ID of the subnet associated with the hostgroup.",
+		},
+
+		"content_view_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description: "ID of the Katello content view associated with this " +
+				"hostgroup. Only applicable on Foreman instances running Katello.",
+		},
+
+		"lifecycle_environment_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description: "ID of the Katello lifecycle environment associated with " +
+				"this hostgroup. Only applicable on Foreman instances running " +
+				"Katello.",
+		},
+
+		"content_source_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description: "ID of the Katello content source (capsule) associated " +
+				"with this hostgroup. Only applicable on Foreman instances " +
+				"running Katello.",
+		},
+
+		"kickstart_repository_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description: "ID of the Katello repository used as the kickstart " +
+				"repository for this hostgroup. Only applicable on Foreman " +
+				"instances running Katello.",
+		},
+
+		"parameters": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Parameters attached to this hostgroup.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"created_at": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"id": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"priority": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"updated_at": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceForemanHostgroupTree() *schema.Resource {
+	return &schema.Resource{
+
+		CreateContext: resourceForemanHostgroupTreeCreate,
+		ReadContext:   resourceForemanHostgroupTreeRead,
+		UpdateContext: resourceForemanHostgroupTreeUpdate,
+		DeleteContext: resourceForemanHostgroupTreeDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+
+			autodoc.MetaAttribute: &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+				Description: fmt.Sprintf(
+					"%s Declares an entire hostgroup hierarchy in one resource, "+
+						"creating and destroying nodes root-first/leaf-first "+
+						"respectively and reconciling edits by matching each desired "+
+						"node to the node with the same \"key\" in the prior state.",
+					autodoc.MetaSummary,
+				),
+			},
+
+			"parent_id": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description: "ID of an existing hostgroup the tree's root node " +
+					"should be attached under. Omit to create the root as a " +
+					"top-level hostgroup.",
+			},
+
+			"node": &schema.Schema{
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "One entry per hostgroup in the tree, keyed by \"key\".",
+				Elem: &schema.Resource{
+					Schema: hostgroupTreeNodeSchema(),
+				},
+			},
+		},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Conversion Helpers
+// -----------------------------------------------------------------------------
+
+// hostgroupTreeNode is the in-memory representation of a single "node"
+// list entry.
+type hostgroupTreeNode struct {
+	Key                    string
+	Path                   string
+	Id                     int
+	ArchitectureId         int
+	ComputeProfileId       int
+	DomainId               int
+	EnvironmentId          int
+	MediaId                int
+	OperatingSystemId      int
+	PartitionTableId       int
+	PuppetCAProxyId        int
+	PuppetProxyId          int
+	RealmId                int
+	SubnetId               int
+	ContentViewId          int
+	LifecycleEnvironmentId int
+	ContentSourceId        int
+	KickstartRepositoryId  int
+	Parameters             []api.ForemanHostgroupParameter
+}
+
+// name returns the final path segment, used as the hostgroup's own name.
+func (n hostgroupTreeNode) name() string {
+	segments := strings.Split(n.Path, "/")
+	return segments[len(segments)-1]
+}
+
+// parentPath returns the path of this node's parent within the tree, or
+// "" if this node is the tree's root.
+func (n hostgroupTreeNode) parentPath() string {
+	idx := strings.LastIndex(n.Path, "/")
+	if idx == -1 {
+		return ""
+	}
+	return n.Path[:idx]
+}
+
+// depth is the number of ancestors this node has within the tree. It is
+// used to order creates root-first and deletes leaf-first.
+func (n hostgroupTreeNode) depth() int {
+	return strings.Count(n.Path, "/")
+}
+
+// expandHostgroupTreeNodes converts the "node" list attribute into a
+// slice of hostgroupTreeNode structs.
+func expandHostgroupTreeNodes(raw []interface{}) []hostgroupTreeNode {
+	nodes := make([]hostgroupTreeNode, len(raw))
+	for i, r := range raw {
+		m := r.(map[string]interface{})
+		nodes[i] = hostgroupTreeNode{
+			Key:                    m["key"].(string),
+			Path:                   m["path"].(string),
+			Id:                     m["id"].(int),
+			ArchitectureId:         m["architecture_id"].(int),
+			ComputeProfileId:       m["compute_profile_id"].(int),
+			DomainId:               m["domain_id"].(int),
+			EnvironmentId:          m["environment_id"].(int),
+			MediaId:                m["medium_id"].(int),
+			OperatingSystemId:      m["operatingsystem_id"].(int),
+			PartitionTableId:       m["ptable_id"].(int),
+			PuppetCAProxyId:        m["puppet_ca_proxy_id"].(int),
+			PuppetProxyId:          m["puppet_proxy_id"].(int),
+			RealmId:                m["realm_id"].(int),
+			SubnetId:               m["subnet_id"].(int),
+			ContentViewId:          m["content_view_id"].(int),
+			LifecycleEnvironmentId: m["lifecycle_environment_id"].(int),
+			ContentSourceId:        m["content_source_id"].(int),
+			KickstartRepositoryId:  m["kickstart_repository_id"].(int),
+			Parameters:             expandHostgroupTreeNodeParameters(m["parameters"].([]interface{})),
+		}
+	}
+	return nodes
+}
+
+// expandHostgroupTreeNodeParameters converts a single node's "parameters"
+// list attribute into a slice of api.ForemanHostgroupParameter, mirroring
+// the conversion resourceForemanHostgroup does off of *schema.ResourceData.
+func expandHostgroupTreeNodeParameters(raw []interface{}) []api.ForemanHostgroupParameter {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	params := make([]api.ForemanHostgroupParameter, len(raw))
+	for i, r := range raw {
+		m := r.(map[string]interface{})
+		params[i] = api.ForemanHostgroupParameter{
+			ID:        int64(m["id"].(int)),
+			Name:      m["name"].(string),
+			Value:     m["value"].(string),
+			CreatedAt: m["created_at"].(string),
+			UpdatedAt: m["updated_at"].(string),
+			Priority:  int64(m["priority"].(int)),
+		}
+	}
+	return params
+}
+
+// flattenHostgroupTreeNodes is the inverse of expandHostgroupTreeNodes,
+// used to write the resolved tree back to the ResourceData.
+func flattenHostgroupTreeNodes(nodes []hostgroupTreeNode) []map[string]interface{} {
+	raw := make([]map[string]interface{}, len(nodes))
+	for i, n := range nodes {
+		raw[i] = map[string]interface{}{
+			"key":                      n.Key,
+			"path":                     n.Path,
+			"id":                       n.Id,
+			"architecture_id":          n.ArchitectureId,
+			"compute_profile_id":       n.ComputeProfileId,
+			"domain_id":                n.DomainId,
+			"environment_id":           n.EnvironmentId,
+			"medium_id":                n.MediaId,
+			"operatingsystem_id":       n.OperatingSystemId,
+			"ptable_id":                n.PartitionTableId,
+			"puppet_ca_proxy_id":       n.PuppetCAProxyId,
+			"puppet_proxy_id":          n.PuppetProxyId,
+			"realm_id":                 n.RealmId,
+			"subnet_id":                n.SubnetId,
+			"content_view_id":          n.ContentViewId,
+			"lifecycle_environment_id": n.LifecycleEnvironmentId,
+			"content_source_id":        n.ContentSourceId,
+			"kickstart_repository_id":  n.KickstartRepositoryId,
+			"parameters":               flattenHostgroupTreeNodeParameters(n.Parameters),
+		}
+	}
+	return raw
+}
+
+// flattenHostgroupTreeNodeParameters is the inverse of
+// expandHostgroupTreeNodeParameters.
+func flattenHostgroupTreeNodeParameters(params []api.ForemanHostgroupParameter) []map[string]interface{} {
+	raw := make([]map[string]interface{}, len(params))
+	for i, p := range params {
+		raw[i] = map[string]interface{}{
+			"name":       p.Name,
+			"value":      p.Value,
+			"created_at": p.CreatedAt,
+			"id":         p.ID,
+			"priority":   p.Priority,
+			"updated_at": p.UpdatedAt,
+		}
+	}
+	return raw
+}
+
+// hostgroupTreeNodeScalars holds every hostgroupTreeNode field except "Id"
+// (populated from Foreman, not config) and "Parameters" (a slice, so it
+// can't take part in a struct ==). It exists solely so equalConfig can
+// compare the remaining fields with a single ==, the same way
+// hostgroupTreeNode itself could before "Parameters" was added.
+type hostgroupTreeNodeScalars struct {
+	Key                    string
+	Path                   string
+	ArchitectureId         int
+	ComputeProfileId       int
+	DomainId               int
+	EnvironmentId          int
+	MediaId                int
+	OperatingSystemId      int
+	PartitionTableId       int
+	PuppetCAProxyId        int
+	PuppetProxyId          int
+	RealmId                int
+	SubnetId               int
+	ContentViewId          int
+	LifecycleEnvironmentId int
+	ContentSourceId        int
+	KickstartRepositoryId  int
+}
+
+func (n hostgroupTreeNode) scalars() hostgroupTreeNodeScalars {
+	return hostgroupTreeNodeScalars{
+		Key:                    n.Key,
+		Path:                   n.Path,
+		ArchitectureId:         n.ArchitectureId,
+		ComputeProfileId:       n.ComputeProfileId,
+		DomainId:               n.DomainId,
+		EnvironmentId:          n.EnvironmentId,
+		MediaId:                n.MediaId,
+		OperatingSystemId:      n.OperatingSystemId,
+		PartitionTableId:       n.PartitionTableId,
+		PuppetCAProxyId:        n.PuppetCAProxyId,
+		PuppetProxyId:          n.PuppetProxyId,
+		RealmId:                n.RealmId,
+		SubnetId:               n.SubnetId,
+		ContentViewId:          n.ContentViewId,
+		LifecycleEnvironmentId: n.LifecycleEnvironmentId,
+		ContentSourceId:        n.ContentSourceId,
+		KickstartRepositoryId:  n.KickstartRepositoryId,
+	}
+}
+
+// equalConfig reports whether n has the same desired configuration as
+// other, ignoring "Id" (which is populated from Foreman, not config).
+func (n hostgroupTreeNode) equalConfig(other hostgroupTreeNode) bool {
+	if n.scalars() != other.scalars() {
+		return false
+	}
+	return equalHostgroupParameters(n.Parameters, other.Parameters)
+}
+
+// equalHostgroupParameters compares two parameter lists by name/value,
+// ignoring server-assigned fields (id/priority/created_at/updated_at) and
+// order, since neither is meaningful for deciding whether an update is
+// needed.
+func equalHostgroupParameters(a, b []api.ForemanHostgroupParameter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	byName := make(map[string]string, len(a))
+	for _, p := range a {
+		byName[p.Name] = p.Value
+	}
+	for _, p := range b {
+		if v, ok := byName[p.Name]; !ok || v != p.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// toForemanHostgroup builds the api.ForemanHostgroup payload for this node,
+// attaching it under parentId (the Foreman ID of its parent node, or the
+// tree's own "parent_id" for the root).
+func (n hostgroupTreeNode) toForemanHostgroup(parentId int) *api.ForemanHostgroup {
+	return &api.ForemanHostgroup{
+		ForemanObject:          api.ForemanObject{Id: n.Id, Name: n.name()},
+		ArchitectureId:         n.ArchitectureId,
+		ComputeProfileId:       n.ComputeProfileId,
+		DomainId:               n.DomainId,
+		EnvironmentId:          n.EnvironmentId,
+		MediaId:                n.MediaId,
+		OperatingSystemId:      n.OperatingSystemId,
+		ParentId:               parentId,
+		PartitionTableId:       n.PartitionTableId,
+		PuppetCAProxyId:        n.PuppetCAProxyId,
+		PuppetProxyId:          n.PuppetProxyId,
+		RealmId:                n.RealmId,
+		SubnetId:               n.SubnetId,
+		ContentViewId:          n.ContentViewId,
+		LifecycleEnvironmentId: n.LifecycleEnvironmentId,
+		ContentSourceId:        n.ContentSourceId,
+		KickstartRepositoryId:  n.KickstartRepositoryId,
+		Parameters:             n.Parameters,
+	}
+}
+
+// validateHostgroupTreeNodes checks that "nodes" describes a well-formed
+// tree before any Foreman API calls are made: every "key" must be unique,
+// and every non-root node's parent path must match another node's "path".
+// Without this, a typo'd path silently resolves to a parentId of 0, which
+// Foreman creates as a new top-level hostgroup instead of failing, and a
+// key collision leaves the colliding nodes sharing a single ID in state.
+func validateHostgroupTreeNodes(nodes []hostgroupTreeNode) error {
+	seenKeys := make(map[string]bool, len(nodes))
+	knownPaths := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		if seenKeys[n.Key] {
+			return fmt.Errorf("hostgroup tree node key %q is not unique", n.Key)
+		}
+		seenKeys[n.Key] = true
+		knownPaths[n.Path] = true
+	}
+
+	for _, n := range nodes {
+		if p := n.parentPath(); p != "" && !knownPaths[p] {
+			return fmt.Errorf(
+				"hostgroup tree node %q (key %q) has parent path %q, which does not "+
+					"match any other node's path",
+				n.Path, n.Key, p,
+			)
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Resource CRUD Operations
+// -----------------------------------------------------------------------------
+
+func resourceForemanHostgroupTreeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Tracef("resource_foreman_hostgroup_tree.go#Create")
+
+	client := meta.(*api.Client)
+	nodes := expandHostgroupTreeNodes(d.Get("node").([]interface{}))
+	rootParentId := d.Get("parent_id").(int)
+
+	if err := validateHostgroupTreeNodes(nodes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Process a copy sorted root-first (shallowest paths before deeper
+	// ones) so a parent is always created before its children, but leave
+	// "nodes" itself in its original, config-authored order - "node" is a
+	// TypeList with Required (not Computed) key/path sub-fields, so list
+	// order is significant to Terraform's diffing and must be preserved
+	// on the way back into state.
+	order := make([]hostgroupTreeNode, len(nodes))
+	copy(order, nodes)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].depth() < order[j].depth() })
+
+	idByPath := make(map[string]int, len(order))
+	idByKey := make(map[string]int, len(order))
+	paramsByKey := make(map[string][]api.ForemanHostgroupParameter, len(order))
+	for _, n := range order {
+		parentId := rootParentId
+		if p := n.parentPath(); p != "" {
+			parentId = idByPath[p]
+		}
+
+		created, err := client.CreateHostgroup(ctx, n.toForemanHostgroup(parentId))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("creating hostgroup tree node %q: %w", n.Path, err))
+		}
+
+		idByPath[n.Path] = created.Id
+		idByKey[n.Key] = created.Id
+		paramsByKey[n.Key] = created.Parameters
+	}
+
+	for i := range nodes {
+		nodes[i].Id = idByKey[nodes[i].Key]
+		nodes[i].Parameters = orderHostgroupParametersLikeConfig(
+			hostgroupParameterNames(nodes[i].Parameters),
+			paramsByKey[nodes[i].Key],
+		)
+	}
+
+	return resourceForemanHostgroupTreeSetState(d, nodes)
+}
+
+func resourceForemanHostgroupTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Tracef("resource_foreman_hostgroup_tree.go#Read")
+
+	client := meta.(*api.Client)
+	nodes := expandHostgroupTreeNodes(d.Get("node").([]interface{}))
+
+	for i, n := range nodes {
+		read, err := client.ReadHostgroup(ctx, n.Id)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("reading hostgroup tree node %q: %w", n.Path, err))
+		}
+
+		nodes[i].ArchitectureId = read.ArchitectureId
+		nodes[i].ComputeProfileId = read.ComputeProfileId
+		nodes[i].DomainId = read.DomainId
+		nodes[i].EnvironmentId = read.EnvironmentId
+		nodes[i].MediaId = read.MediaId
+		nodes[i].OperatingSystemId = read.OperatingSystemId
+		nodes[i].PartitionTableId = read.PartitionTableId
+		nodes[i].PuppetCAProxyId = read.PuppetCAProxyId
+		nodes[i].PuppetProxyId = read.PuppetProxyId
+		nodes[i].RealmId = read.RealmId
+		nodes[i].SubnetId = read.SubnetId
+		nodes[i].ContentViewId = read.ContentViewId
+		nodes[i].LifecycleEnvironmentId = read.LifecycleEnvironmentId
+		nodes[i].ContentSourceId = read.ContentSourceId
+		nodes[i].KickstartRepositoryId = read.KickstartRepositoryId
+		nodes[i].Parameters = orderHostgroupParametersLikeConfig(
+			hostgroupParameterNames(nodes[i].Parameters),
+			read.Parameters,
+		)
+	}
+
+	return resourceForemanHostgroupTreeSetState(d, nodes)
+}
+
+func resourceForemanHostgroupTreeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Tracef("resource_foreman_hostgroup_tree.go#Update")
+
+	client := meta.(*api.Client)
+
+	oldRaw, newRaw := d.GetChange("node")
+	oldNodes := expandHostgroupTreeNodes(oldRaw.([]interface{}))
+	newNodes := expandHostgroupTreeNodes(newRaw.([]interface{}))
+	rootParentId := d.Get("parent_id").(int)
+
+	if err := validateHostgroupTreeNodes(newNodes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	oldByKey := make(map[string]hostgroupTreeNode, len(oldNodes))
+	for _, n := range oldNodes {
+		oldByKey[n.Key] = n
+	}
+	newByKey := make(map[string]hostgroupTreeNode, len(newNodes))
+	for _, n := range newNodes {
+		newByKey[n.Key] = n
+	}
+
+	// leaf-first: deepest paths before shallower ones, so children are
+	// gone before their parent is removed. This is keyed on the OLD tree's
+	// paths, since that's the hierarchy Foreman actually has right now.
+	// Sorting a copy here, rather than oldNodes itself, since oldNodes is
+	// only read from below and its order doesn't otherwise matter, but
+	// keeping the pattern consistent with newNodes below avoids surprises
+	// if that changes.
+	deleteOrder := make([]hostgroupTreeNode, len(oldNodes))
+	copy(deleteOrder, oldNodes)
+	sort.SliceStable(deleteOrder, func(i, j int) bool { return deleteOrder[i].depth() > deleteOrder[j].depth() })
+	for _, n := range deleteOrder {
+		if _, stillWanted := newByKey[n.Key]; !stillWanted {
+			if err := client.DeleteHostgroup(ctx, n.Id); err != nil {
+				return diag.FromErr(fmt.Errorf("deleting hostgroup tree node %q: %w", n.Path, err))
+			}
+		}
+	}
+
+	// Process a copy sorted root-first (shallowest paths before deeper
+	// ones) so a parent exists in Foreman (and in idByPath) before its
+	// children are created/updated, but leave "newNodes" itself in its
+	// original, config-authored order - "node" is a TypeList with
+	// Required (not Computed) key/path sub-fields, so list order is
+	// significant to Terraform's diffing and must be preserved on the way
+	// back into state.
+	order := make([]hostgroupTreeNode, len(newNodes))
+	copy(order, newNodes)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].depth() < order[j].depth() })
+
+	idByPath := make(map[string]int, len(order))
+	idByKey := make(map[string]int, len(order))
+	paramsByKey := make(map[string][]api.ForemanHostgroupParameter, len(order))
+	for _, n := range order {
+		// matched by key, NOT path, so a node keeps its identity (and gets
+		// an UpdateHostgroup call) even when "path" changes because it was
+		// re-parented or renamed; only a key with no match on either side
+		// is a genuine destroy/create
+		old, existed := oldByKey[n.Key]
+
+		parentId := rootParentId
+		if p := n.parentPath(); p != "" {
+			parentId = idByPath[p]
+		}
+
+		if !existed {
+			created, err := client.CreateHostgroup(ctx, n.toForemanHostgroup(parentId))
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("creating hostgroup tree node %q: %w", n.Path, err))
+			}
+			idByPath[n.Path] = created.Id
+			idByKey[n.Key] = created.Id
+			paramsByKey[n.Key] = orderHostgroupParametersLikeConfig(hostgroupParameterNames(n.Parameters), created.Parameters)
+			continue
+		}
+
+		n.Id = old.Id
+		idByPath[n.Path] = old.Id
+		idByKey[n.Key] = old.Id
+		paramsByKey[n.Key] = old.Parameters
+
+		if !old.equalConfig(n) {
+			updated, err := client.UpdateHostgroup(ctx, n.toForemanHostgroup(parentId))
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("updating hostgroup tree node %q: %w", n.Path, err))
+			}
+			idByPath[n.Path] = updated.Id
+			idByKey[n.Key] = updated.Id
+			paramsByKey[n.Key] = orderHostgroupParametersLikeConfig(hostgroupParameterNames(n.Parameters), updated.Parameters)
+		}
+	}
+
+	for i := range newNodes {
+		newNodes[i].Id = idByKey[newNodes[i].Key]
+		newNodes[i].Parameters = paramsByKey[newNodes[i].Key]
+	}
+
+	return resourceForemanHostgroupTreeSetState(d, newNodes)
+}
+
+func resourceForemanHostgroupTreeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Tracef("resource_foreman_hostgroup_tree.go#Delete")
+
+	client := meta.(*api.Client)
+	nodes := expandHostgroupTreeNodes(d.Get("node").([]interface{}))
+
+	// leaf-first: deepest paths before shallower ones
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].depth() > nodes[j].depth() })
+
+	for _, n := range nodes {
+		if err := client.DeleteHostgroup(ctx, n.Id); err != nil {
+			return diag.FromErr(fmt.Errorf("deleting hostgroup tree node %q: %w", n.Path, err))
+		}
+	}
+
+	// NOTE(ALL): d.SetId("") is automatically called by terraform assuming
+	//   delete returns no errors
+	return nil
+}
+
+// resourceForemanHostgroupTreeSetState writes the resolved set of nodes
+// back to the ResourceData and derives the tree's own ID from its root
+// node (the node with no "/" in its path).
+func resourceForemanHostgroupTreeSetState(d *schema.ResourceData, nodes []hostgroupTreeNode) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, n := range nodes {
+		if n.parentPath() == "" {
+			d.SetId(strconv.Itoa(n.Id))
+			break
+		}
+	}
+
+	if err := d.Set("node", flattenHostgroupTreeNodes(nodes)); err != nil {
+		diags = append(diags, diag.FromErr(fmt.Errorf("error setting hostgroup_tree node: %w", err))...)
+	}
+
+	return diags
+}
@@ -0,0 +1,62 @@
+package foreman
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccForemanHostgroupTree_reparent exercises moving the "frontend" leaf
+// from one parent ("web") to another ("api") within the same tree.
+// "frontend" keeps its key across the move, so this should update its
+// parent_id in place rather than destroying and recreating it.
+func TestAccForemanHostgroupTree_reparent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccForemanHostgroupTreeConfig("web"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("foreman_hostgroup_tree.test", "node.2.path", "root/web/frontend"),
+				),
+			},
+			{
+				Config: testAccForemanHostgroupTreeConfig("api"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("foreman_hostgroup_tree.test", "node.2.path", "root/api/frontend"),
+					resource.TestCheckResourceAttr("foreman_hostgroup_tree.test", "node.2.key", "frontend"),
+				),
+			},
+		},
+	})
+}
+
+// testAccForemanHostgroupTreeConfig builds a three-level tree with both
+// "web" and "api" under "root" and "frontend" under parentName, letting
+// the caller move "frontend" between the two parents by varying
+// parentName between test steps. Every key is held constant across both
+// configs so the test exercises a move rather than a destroy/create.
+func testAccForemanHostgroupTreeConfig(parentName string) string {
+	return fmt.Sprintf(`
+resource "foreman_hostgroup_tree" "test" {
+  node {
+    key  = "root"
+    path = "root"
+  }
+  node {
+    key  = "web"
+    path = "root/web"
+  }
+  node {
+    key  = "frontend"
+    path = "root/%[1]s/frontend"
+  }
+  node {
+    key  = "api"
+    path = "root/api"
+  }
+}
+`, parentName)
+}